@@ -0,0 +1,106 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2022 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+// Command i18n-extract scans a Go module for calls to i18n.G, i18n.NG,
+// i18n.GT and i18n.NGT and writes the resulting msgids to a POT or
+// JSON catalog template, optionally merging into an existing one.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/flotter/golib/i18n/extract"
+)
+
+func main() {
+	var (
+		dir    = flag.String("dir", ".", "module directory to scan")
+		format = flag.String("format", "json", `catalog format to write: "json" or "pot"`)
+		out    = flag.String("out", "-", `output file, or "-" for stdout`)
+		merge  = flag.String("merge", "", "existing catalog file to merge into, preserving its translations")
+	)
+	flag.Parse()
+
+	if err := run(*dir, *format, *out, *merge); err != nil {
+		fmt.Fprintln(os.Stderr, "i18n-extract:", err)
+		os.Exit(1)
+	}
+}
+
+func run(dir, format, out, mergePath string) error {
+	msgs, diags, err := extract.Extract(dir, extract.DefaultConfig())
+	if err != nil {
+		return err
+	}
+	for _, d := range diags {
+		fmt.Fprintln(os.Stderr, d.Message)
+	}
+
+	w := os.Stdout
+	if out != "-" {
+		f, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	switch format {
+	case "pot":
+		if mergePath != "" {
+			existing, err := os.Open(mergePath)
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			if existing != nil {
+				defer existing.Close()
+			}
+			merged, err := extract.MergePOT(existing, msgs)
+			if err != nil {
+				return err
+			}
+			return extract.WritePOTTranslated(w, msgs, merged)
+		}
+		return extract.WritePOT(w, msgs)
+	case "json":
+		if mergePath != "" {
+			existing, err := os.Open(mergePath)
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			if existing != nil {
+				defer existing.Close()
+			}
+			merged, err := extract.MergeJSON(existing, msgs)
+			if err != nil {
+				return err
+			}
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			return enc.Encode(merged)
+		}
+		return extract.WriteJSON(w, msgs)
+	default:
+		return fmt.Errorf("unknown -format %q, want %q or %q", format, "json", "pot")
+	}
+}
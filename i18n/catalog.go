@@ -0,0 +1,414 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2022 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package i18n
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/flotter/golib/i18n/plural"
+	"gopkg.in/yaml.v3"
+)
+
+// langKeyType is an unexported type for the context key under which the
+// active language tag is stored, to avoid collisions with keys defined
+// in other packages.
+type langKeyType struct{}
+
+var langKey langKeyType
+
+// WithLang returns a copy of ctx carrying lang as the language to use
+// for GCtx and NGCtx lookups.
+func WithLang(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, langKey, lang)
+}
+
+// LangFromContext returns the language tag previously attached to ctx
+// with WithLang, and whether one was found.
+func LangFromContext(ctx context.Context) (string, bool) {
+	lang, ok := ctx.Value(langKey).(string)
+	return lang, ok
+}
+
+// Catalog is a MarkerAPI implementation backed by message catalogs
+// loaded from disk. Use NewCatalog to build one.
+type Catalog struct {
+	defaultLang  string
+	fallbackLang string
+	messages     map[string]map[string]string
+	// pluralMessages holds msgids declared with per-CLDR-category
+	// translations, keyed lang -> msgid -> category -> text.
+	pluralMessages map[string]map[string]map[string]string
+}
+
+// CatalogOption configures a Catalog constructed by NewCatalog.
+type CatalogOption func(*catalogConfig)
+
+type catalogConfig struct {
+	jsonDirs     []string
+	yamlDirs     []string
+	moFiles      []string
+	defaultLang  string
+	fallbackLang string
+}
+
+// WithJSONDir registers a directory of BCP-47 named JSON catalog files
+// (e.g. "en-US.json") to be loaded by NewCatalog.
+func WithJSONDir(path string) CatalogOption {
+	return func(c *catalogConfig) {
+		c.jsonDirs = append(c.jsonDirs, path)
+	}
+}
+
+// WithYAMLDir registers a directory of BCP-47 named YAML catalog files
+// (e.g. "fr.yaml") to be loaded by NewCatalog.
+func WithYAMLDir(path string) CatalogOption {
+	return func(c *catalogConfig) {
+		c.yamlDirs = append(c.yamlDirs, path)
+	}
+}
+
+// WithGettextMO registers a compiled gettext catalog file (e.g.
+// "de.mo") to be loaded by NewCatalog. The language tag is taken from
+// the file's base name.
+func WithGettextMO(path string) CatalogOption {
+	return func(c *catalogConfig) {
+		c.moFiles = append(c.moFiles, path)
+	}
+}
+
+// WithDefaultLang sets the language tag used by GCtx/NGCtx when the
+// context carries none.
+func WithDefaultLang(tag string) CatalogOption {
+	return func(c *catalogConfig) {
+		c.defaultLang = tag
+	}
+}
+
+// WithFallbackLang sets the language tag consulted when a msgid is
+// missing from the requested language's catalog.
+func WithFallbackLang(tag string) CatalogOption {
+	return func(c *catalogConfig) {
+		c.fallbackLang = tag
+	}
+}
+
+// NewCatalog builds a Catalog from the given options, loading and
+// flattening every registered catalog file. It panics if a registered
+// file cannot be parsed, since a broken catalog is a build-time error,
+// not a runtime one.
+func NewCatalog(opts ...CatalogOption) *Catalog {
+	cfg := catalogConfig{
+		defaultLang: "en",
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	cat := &Catalog{
+		defaultLang:    cfg.defaultLang,
+		fallbackLang:   cfg.fallbackLang,
+		messages:       make(map[string]map[string]string),
+		pluralMessages: make(map[string]map[string]map[string]string),
+	}
+
+	for _, dir := range cfg.jsonDirs {
+		cat.loadDir(dir, ".json", loadJSONFile)
+	}
+	for _, dir := range cfg.yamlDirs {
+		cat.loadDir(dir, ".yaml", loadYAMLFile)
+	}
+	for _, path := range cfg.moFiles {
+		lang := langFromFilename(path)
+		msgs, plurals, err := loadMOFile(path, lang)
+		if err != nil {
+			panic(fmt.Sprintf("i18n: cannot load %q: %v", path, err))
+		}
+		cat.merge(lang, msgs, plurals)
+	}
+
+	return cat
+}
+
+func (c *Catalog) loadDir(dir, ext string, load func(string) (map[string]string, map[string]map[string]string, error)) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		panic(fmt.Sprintf("i18n: cannot read catalog directory %q: %v", dir, err))
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ext {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		msgs, plurals, err := load(path)
+		if err != nil {
+			panic(fmt.Sprintf("i18n: cannot load %q: %v", path, err))
+		}
+		c.merge(langFromFilename(path), msgs, plurals)
+	}
+}
+
+func (c *Catalog) merge(lang string, msgs map[string]string, plurals map[string]map[string]string) {
+	dst, ok := c.messages[lang]
+	if !ok {
+		dst = make(map[string]string)
+		c.messages[lang] = dst
+	}
+	for k, v := range msgs {
+		dst[k] = v
+	}
+
+	if len(plurals) == 0 {
+		return
+	}
+	pdst, ok := c.pluralMessages[lang]
+	if !ok {
+		pdst = make(map[string]map[string]string)
+		c.pluralMessages[lang] = pdst
+	}
+	for msgid, forms := range plurals {
+		pdst[msgid] = forms
+	}
+}
+
+// langFromFilename derives a BCP-47 language tag from a catalog file
+// name such as "en-US.json" or "fr.yaml".
+func langFromFilename(path string) string {
+	base := filepath.Base(path)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+func loadJSONFile(path string) (map[string]string, map[string]map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, nil, err
+	}
+	flat := make(map[string]string)
+	plurals := make(map[string]map[string]string)
+	flatten("", raw, flat, plurals)
+	return flat, plurals, nil
+}
+
+func loadYAMLFile(path string) (map[string]string, map[string]map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, nil, err
+	}
+	flat := make(map[string]string)
+	plurals := make(map[string]map[string]string)
+	flatten("", raw, flat, plurals)
+	return flat, plurals, nil
+}
+
+// flatten walks a nested JSON/YAML document, writing leaf string values
+// into out keyed by their dotted path (e.g. "errors.notFound"). A
+// nested object whose keys are entirely CLDR plural categories (see
+// package i18n/plural) is treated as a plural group instead: its
+// forms are recorded in plurals under the path leading to it, rather
+// than being flattened further.
+func flatten(prefix string, node map[string]interface{}, out map[string]string, plurals map[string]map[string]string) {
+	for k, v := range node {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		switch val := v.(type) {
+		case map[string]interface{}:
+			if forms, ok := asPluralGroup(val); ok {
+				plurals[key] = forms
+				continue
+			}
+			flatten(key, val, out, plurals)
+		case string:
+			out[key] = val
+		default:
+			out[key] = fmt.Sprintf("%v", val)
+		}
+	}
+}
+
+// asPluralGroup reports whether node's keys are all CLDR plural
+// categories, and if so returns them as a flat string map.
+func asPluralGroup(node map[string]interface{}) (map[string]string, bool) {
+	if len(node) == 0 {
+		return nil, false
+	}
+	forms := make(map[string]string, len(node))
+	for k, v := range node {
+		if !plural.IsCategory(k) {
+			return nil, false
+		}
+		s, ok := v.(string)
+		if !ok {
+			return nil, false
+		}
+		forms[k] = s
+	}
+	return forms, true
+}
+
+// lookup resolves msgid for lang, falling back to the fallback language
+// and then to msgid itself. It notifies the package observer, if one
+// is registered, of fallback and missing lookups.
+func (c *Catalog) lookup(lang, msgid string) (string, bool) {
+	if s, ok := c.lookupFlatOrPlural(lang, msgid); ok {
+		return s, true
+	}
+	if c.fallbackLang != "" && c.fallbackLang != lang {
+		if s, ok := c.lookupFlatOrPlural(c.fallbackLang, msgid); ok {
+			if observer != nil {
+				observer.OnFallback(lang, msgid)
+			}
+			return s, true
+		}
+	}
+	if observer != nil {
+		observer.OnMissing(lang, msgid)
+	}
+	return msgid, false
+}
+
+// lookupFlatOrPlural resolves msgid against lang's flat messages, or,
+// failing that, the "other" (or "one") form of lang's declared
+// per-category group for msgid. It exists so callers that only want a
+// single string for msgid (G, and NG's PluralAPI-driven collapse to
+// msgid/msgidPlural) still get a translation for msgids that were only
+// ever declared as a plural group, rather than falling through to the
+// untranslated msgid.
+func (c *Catalog) lookupFlatOrPlural(lang, msgid string) (string, bool) {
+	if msgs, ok := c.messages[lang]; ok {
+		if s, ok := msgs[msgid]; ok {
+			return s, true
+		}
+	}
+	if forms, ok := c.pluralMessages[lang][msgid]; ok {
+		if s, ok := forms["other"]; ok {
+			return s, true
+		}
+		if s, ok := forms["one"]; ok {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+// G implements MarkerAPI using the Catalog's default language.
+func (c *Catalog) G(msgid string) string {
+	s, _ := c.lookup(c.defaultLang, msgid)
+	return s
+}
+
+// NG implements MarkerAPI using the Catalog's default language and, per
+// PluralAPI, the CLDR plural category for n in that language.
+func (c *Catalog) NG(msgid, msgidPlural string, n int) string {
+	return c.ngFor(c.defaultLang, msgid, msgidPlural, n)
+}
+
+// PluralForm implements PluralAPI. An empty lang means "the Catalog's
+// default language".
+func (c *Catalog) PluralForm(lang string, n interface{}) string {
+	if lang == "" {
+		lang = c.defaultLang
+	}
+	return plural.Category(lang, n)
+}
+
+// GCtx is the context-aware counterpart of G: it takes the language to
+// translate into from ctx, as attached by WithLang, falling back to the
+// Catalog's default language when ctx carries none.
+func (c *Catalog) GCtx(ctx context.Context, msgid string) string {
+	lang, ok := LangFromContext(ctx)
+	if !ok {
+		lang = c.defaultLang
+	}
+	s, _ := c.lookup(lang, msgid)
+	return s
+}
+
+// NGCtx is the context-aware counterpart of NG.
+func (c *Catalog) NGCtx(ctx context.Context, msgid, msgidPlural string, n int) string {
+	lang, ok := LangFromContext(ctx)
+	if !ok {
+		lang = c.defaultLang
+	}
+	return c.ngFor(lang, msgid, msgidPlural, n)
+}
+
+// ngFor resolves the plural translation of msgid for lang and n. When
+// the catalog declares a full CLDR-category group for msgid, the
+// category selects the form directly; when it declares only a plain
+// two-form entry (or none), the category is collapsed the legacy way:
+// "one" maps to msgid, everything else to msgidPlural.
+func (c *Catalog) ngFor(lang, msgid, msgidPlural string, n int) string {
+	category := plural.Category(lang, n)
+
+	if forms, ok := c.lookupPluralGroup(lang, msgid); ok {
+		if s, ok := forms[category]; ok {
+			return s
+		}
+		if s, ok := forms["other"]; ok {
+			if observer != nil {
+				observer.OnMalformedPlural(lang, msgid, n)
+			}
+			return s
+		}
+	}
+
+	if category == "one" {
+		if s, ok := c.lookup(lang, msgid); ok {
+			return s
+		}
+		return msgid
+	}
+	if s, ok := c.lookup(lang, msgidPlural); ok {
+		return s
+	}
+	return msgidPlural
+}
+
+// lookupPluralGroup resolves the CLDR-category group for msgid in
+// lang, falling back to the fallback language.
+func (c *Catalog) lookupPluralGroup(lang, msgid string) (map[string]string, bool) {
+	if groups, ok := c.pluralMessages[lang]; ok {
+		if forms, ok := groups[msgid]; ok {
+			return forms, true
+		}
+	}
+	if c.fallbackLang != "" && c.fallbackLang != lang {
+		if groups, ok := c.pluralMessages[c.fallbackLang]; ok {
+			if forms, ok := groups[msgid]; ok {
+				return forms, true
+			}
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,72 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2022 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package i18n
+
+import "testing"
+
+func TestCatalogJSONDottedKeysAndFallback(t *testing.T) {
+	dir := t.TempDir()
+	writeJSONCatalog(t, dir, "en.json", `{"errors": {"notFound": "not found"}, "hello": "hello"}`)
+	writeJSONCatalog(t, dir, "fr.json", `{"hello": "bonjour"}`)
+
+	cat := NewCatalog(WithJSONDir(dir), WithDefaultLang("fr"), WithFallbackLang("en"))
+
+	if got, want := cat.G("hello"), "bonjour"; got != want {
+		t.Errorf("G(hello) = %q, want %q", got, want)
+	}
+	if got, want := cat.G("errors.notFound"), "not found"; got != want {
+		t.Errorf("G(errors.notFound) = %q, want %q (from fallback lang)", got, want)
+	}
+	if got, want := cat.G("missing"), "missing"; got != want {
+		t.Errorf("G(missing) = %q, want %q (untranslated msgid)", got, want)
+	}
+}
+
+func TestCatalogJSONPluralGroup(t *testing.T) {
+	dir := t.TempDir()
+	writeJSONCatalog(t, dir, "ru.json", `{"one file": {"one": "один файл", "few": "%d файла", "many": "%d файлов"}}`)
+
+	cat := NewCatalog(WithJSONDir(dir), WithDefaultLang("ru"))
+
+	cases := []struct {
+		n    int
+		want string
+	}{{1, "один файл"}, {2, "%d файла"}, {5, "%d файлов"}}
+	for _, c := range cases {
+		if got := cat.NG("one file", "%d files", c.n); got != c.want {
+			t.Errorf("NG(..., %d) = %q, want %q", c.n, got, c.want)
+		}
+	}
+}
+
+func TestCatalogNGTwoFormFallback(t *testing.T) {
+	dir := t.TempDir()
+	writeJSONCatalog(t, dir, "fr.json", `{"one file": "un fichier", "%d files": "%d fichiers"}`)
+
+	cat := NewCatalog(WithJSONDir(dir), WithDefaultLang("fr"))
+
+	// French treats 0 as "one", unlike the English fallback rule.
+	if got, want := cat.NG("one file", "%d files", 0), "un fichier"; got != want {
+		t.Errorf("NG(..., 0) = %q, want %q", got, want)
+	}
+	if got, want := cat.NG("one file", "%d files", 2), "%d fichiers"; got != want {
+		t.Errorf("NG(..., 2) = %q, want %q", got, want)
+	}
+}
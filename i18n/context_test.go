@@ -0,0 +1,77 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2022 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package i18n
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeJSONCatalog(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPackageGCtxNGCtxDispatchThroughContextAPI(t *testing.T) {
+	dir := t.TempDir()
+	writeJSONCatalog(t, dir, "en.json", `{"hello": "hello", "one file": "one file", "%d files": "%d files"}`)
+	writeJSONCatalog(t, dir, "fr.json", `{"hello": "bonjour", "one file": "un fichier", "%d files": "%d fichiers"}`)
+
+	cat := NewCatalog(WithJSONDir(dir), WithDefaultLang("en"))
+	Initialise(cat)
+	t.Cleanup(func() { Initialise(nil) })
+
+	ctx := WithLang(context.Background(), "fr")
+	if got, want := GCtx(ctx, "hello"), "bonjour"; got != want {
+		t.Errorf("GCtx(fr, hello) = %q, want %q", got, want)
+	}
+	if got, want := NGCtx(ctx, "one file", "%d files", 3), "%d fichiers"; got != want {
+		t.Errorf("NGCtx(fr, ..., 3) = %q, want %q", got, want)
+	}
+	if got, want := GCtx(context.Background(), "hello"), "hello"; got != want {
+		t.Errorf("GCtx(no lang, hello) = %q, want %q", got, want)
+	}
+}
+
+func TestPackageGCtxWithoutContextAPIFallsBackToG(t *testing.T) {
+	Initialise(pluralOnlyMarker{})
+	t.Cleanup(func() { Initialise(nil) })
+
+	if got, want := GCtx(context.Background(), "hello"), "plain-hello"; got != want {
+		t.Errorf("GCtx(no ContextAPI) = %q, want %q", got, want)
+	}
+}
+
+// pluralOnlyMarker is a MarkerAPI that does not implement ContextAPI,
+// used to confirm GCtx/NGCtx degrade to G/NG rather than panicking.
+type pluralOnlyMarker struct{}
+
+func (pluralOnlyMarker) G(msgid string) string { return "plain-" + msgid }
+
+func (pluralOnlyMarker) NG(msgid, msgidPlural string, n int) string {
+	if n == 1 {
+		return msgid
+	}
+	return msgidPlural
+}
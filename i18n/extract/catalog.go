@@ -0,0 +1,189 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2022 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package extract
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// WritePOT writes msgs as a GNU gettext POT template, with each
+// message's source locations recorded as translator comments.
+func WritePOT(w io.Writer, msgs []Message) error {
+	return WritePOTTranslated(w, msgs, nil)
+}
+
+// WritePOTTranslated writes msgs as a gettext catalog, filling in
+// msgstr (or msgstr[0], msgstr[1], ... for plural messages) from
+// translations (keyed by msgid, one string per plural form in gettext's
+// msgstr[N] order) where available and leaving it empty otherwise. It
+// is used by the -merge flag to re-emit a catalog that keeps its
+// existing translations.
+func WritePOTTranslated(w io.Writer, msgs []Message, translations map[string][]string) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, `msgid ""`)
+	fmt.Fprintln(bw, `msgstr ""`)
+	fmt.Fprintln(bw, `"Content-Type: text/plain; charset=UTF-8\n"`)
+	for _, m := range msgs {
+		fmt.Fprintln(bw)
+		for _, loc := range m.Locations {
+			fmt.Fprintf(bw, "#: %s\n", loc)
+		}
+		fmt.Fprintf(bw, "msgid %s\n", quotePOT(m.MsgID))
+		forms := translations[m.MsgID]
+		if m.MsgIDPlural != "" {
+			fmt.Fprintf(bw, "msgid_plural %s\n", quotePOT(m.MsgIDPlural))
+			n := len(forms)
+			if n < 2 {
+				n = 2
+			}
+			for i := 0; i < n; i++ {
+				var form string
+				if i < len(forms) {
+					form = forms[i]
+				}
+				fmt.Fprintf(bw, "msgstr[%d] %s\n", i, quotePOT(form))
+			}
+		} else {
+			var form string
+			if len(forms) > 0 {
+				form = forms[0]
+			}
+			fmt.Fprintf(bw, "msgstr %s\n", quotePOT(form))
+		}
+	}
+	return bw.Flush()
+}
+
+func quotePOT(s string) string {
+	return strconv.Quote(s)
+}
+
+// WriteJSON writes msgs as a flat JSON catalog skeleton: msgid keys
+// mapped to an empty translation, ready for a translator to fill in.
+// Plural messages are written as an object with "one"/"other" keys.
+func WriteJSON(w io.Writer, msgs []Message) error {
+	out := make(map[string]interface{}, len(msgs))
+	for _, m := range msgs {
+		if m.MsgIDPlural != "" {
+			out[m.MsgID] = map[string]string{"one": "", "other": ""}
+		} else {
+			out[m.MsgID] = ""
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// MergeJSON updates an existing JSON catalog (read from existing) so
+// that it contains exactly the msgids in msgs: translator-supplied
+// values for msgids that still exist are preserved, new msgids are
+// added with an empty translation, and msgids no longer present in
+// msgs are dropped. This mirrors msgmerge's behaviour for gettext
+// catalogs.
+func MergeJSON(existing io.Reader, msgs []Message) (map[string]interface{}, error) {
+	current := make(map[string]interface{})
+	if existing != nil {
+		if err := json.NewDecoder(existing).Decode(&current); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("extract: parsing existing catalog: %w", err)
+		}
+	}
+
+	merged := make(map[string]interface{}, len(msgs))
+	for _, m := range msgs {
+		if prev, ok := current[m.MsgID]; ok {
+			merged[m.MsgID] = prev
+			continue
+		}
+		if m.MsgIDPlural != "" {
+			merged[m.MsgID] = map[string]string{"one": "", "other": ""}
+		} else {
+			merged[m.MsgID] = ""
+		}
+	}
+	return merged, nil
+}
+
+var potMsgidRe = regexp.MustCompile(`^msgid "(.*)"$`)
+var potMsgstrRe = regexp.MustCompile(`^msgstr "(.*)"$`)
+var potMsgstrIndexRe = regexp.MustCompile(`^msgstr\[(\d+)\] "(.*)"$`)
+
+// MergePOT updates an existing POT/PO file the same way MergeJSON does,
+// but for gettext's line-oriented format: it keeps msgstr (and, for
+// plural messages, every msgstr[N]) value for msgids still present in
+// msgs and drops entries for msgids that are no longer used. The
+// returned forms are in gettext's msgstr[N] order; a non-plural
+// message has at most one.
+func MergePOT(existing io.Reader, msgs []Message) (map[string][]string, error) {
+	translations := make(map[string][]string)
+	// Strings are read back with the same quoting quotePOT writes, so
+	// escaped msgids match and kept values are not escaped twice.
+	unquote := func(s string) string {
+		if u, err := strconv.Unquote(`"` + s + `"`); err == nil {
+			return u
+		}
+		return s
+	}
+	if existing != nil {
+		scanner := bufio.NewScanner(existing)
+		var pendingMsgid string
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if m := potMsgidRe.FindStringSubmatch(line); m != nil {
+				pendingMsgid = unquote(m[1])
+				continue
+			}
+			if pendingMsgid == "" {
+				continue
+			}
+			if m := potMsgstrRe.FindStringSubmatch(line); m != nil {
+				translations[pendingMsgid] = []string{unquote(m[1])}
+				continue
+			}
+			if m := potMsgstrIndexRe.FindStringSubmatch(line); m != nil {
+				idx, err := strconv.Atoi(m[1])
+				if err != nil {
+					continue
+				}
+				forms := translations[pendingMsgid]
+				for len(forms) <= idx {
+					forms = append(forms, "")
+				}
+				forms[idx] = unquote(m[2])
+				translations[pendingMsgid] = forms
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("extract: parsing existing catalog: %w", err)
+		}
+	}
+
+	merged := make(map[string][]string, len(msgs))
+	for _, m := range msgs {
+		merged[m.MsgID] = translations[m.MsgID]
+	}
+	return merged, nil
+}
@@ -0,0 +1,102 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2022 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package extract
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMergePOTPreservesPluralForms(t *testing.T) {
+	existing := strings.NewReader(`msgid ""
+msgstr ""
+"Content-Type: text/plain; charset=UTF-8\n"
+
+#: main.go:1
+msgid "one file"
+msgid_plural "%d files"
+msgstr[0] "un fichier"
+msgstr[1] "%d fichiers"
+`)
+
+	msgs := []Message{{MsgID: "one file", MsgIDPlural: "%d files"}}
+	merged, err := MergePOT(existing, msgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"un fichier", "%d fichiers"}
+	got := merged["one file"]
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("merged[%q] = %v, want %v", "one file", got, want)
+	}
+}
+
+func TestMergePOTPreservesSingularForm(t *testing.T) {
+	existing := strings.NewReader(`msgid ""
+msgstr ""
+
+msgid "hello"
+msgstr "bonjour"
+`)
+
+	msgs := []Message{{MsgID: "hello"}}
+	merged, err := MergePOT(existing, msgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := merged["hello"], []string{"bonjour"}; len(got) != 1 || got[0] != want[0] {
+		t.Errorf("merged[%q] = %v, want %v", "hello", got, want)
+	}
+}
+
+func TestMergePOTDropsRemovedMsgid(t *testing.T) {
+	existing := strings.NewReader(`msgid "gone"
+msgstr "disparu"
+`)
+
+	merged, err := MergePOT(existing, []Message{{MsgID: "still here"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := merged["gone"]; ok {
+		t.Errorf("merged still contains removed msgid %q", "gone")
+	}
+	if got := merged["still here"]; got != nil {
+		t.Errorf("merged[%q] = %v, want nil (no prior translation)", "still here", got)
+	}
+}
+
+func TestWritePOTTranslatedRoundTripsPluralForms(t *testing.T) {
+	msgs := []Message{{MsgID: "one file", MsgIDPlural: "%d files"}}
+	translations := map[string][]string{"one file": {"un fichier", "%d fichiers"}}
+
+	var buf strings.Builder
+	if err := WritePOTTranslated(&buf, msgs, translations); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{`msgstr[0] "un fichier"`, `msgstr[1] "%d fichiers"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q, got:\n%s", want, out)
+		}
+	}
+}
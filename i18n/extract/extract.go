@@ -0,0 +1,218 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2022 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+// Package extract walks Go source looking for calls into package i18n
+// (G, NG, GT, NGT and configurable aliases) and turns them into a
+// translation catalog template, so that adopters of i18n have a
+// maintainable path from source to catalogs instead of hand-curating
+// msgids.
+package extract
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Message is one extracted translatable string.
+type Message struct {
+	MsgID       string
+	MsgIDPlural string // empty unless extracted from a plural call
+	Locations   []string
+}
+
+// Diagnostic reports a call into a translation function whose msgid (or
+// msgid_plural) argument was not a string literal, and therefore could
+// not be extracted.
+type Diagnostic struct {
+	Position string
+	Message  string
+}
+
+// Config controls which calls Extract recognises as translation calls:
+// Package is the import path calls must be qualified with, SingularFns
+// lists functions taking a single msgid (like G), and PluralFns lists
+// functions taking msgid and msgid_plural (like NG).
+type Config struct {
+	Package     string // import path calls must be qualified with, e.g. "github.com/flotter/golib/i18n"
+	SingularFns []string
+	PluralFns   []string // functions whose second argument is msgid_plural
+}
+
+// DefaultConfig recognises the standard i18n.G/NG/GT/NGT call sites.
+func DefaultConfig() Config {
+	return Config{
+		Package:     "github.com/flotter/golib/i18n",
+		SingularFns: []string{"G", "GT"},
+		PluralFns:   []string{"NG", "NGT"},
+	}
+}
+
+// Extract walks every .go file under dir (skipping _test.go files and
+// directories named "vendor" or starting with "."), collecting translatable strings
+// according to cfg. Identical msgids have their locations merged into
+// a single Message.
+func Extract(dir string, cfg Config) ([]Message, []Diagnostic, error) {
+	byID := make(map[string]*Message)
+	var order []string
+	var diags []Diagnostic
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if name == "vendor" || (name != "." && strings.HasPrefix(name, ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return fmt.Errorf("extract: parsing %s: %w", path, err)
+		}
+
+		alias := packageAlias(file, cfg.Package)
+		if alias == "" {
+			return nil
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			pkgIdent, ok := sel.X.(*ast.Ident)
+			if !ok || pkgIdent.Name != alias {
+				return true
+			}
+
+			pos := fset.Position(call.Pos())
+			loc := fmt.Sprintf("%s:%d", path, pos.Line)
+
+			switch {
+			case contains(cfg.SingularFns, sel.Sel.Name):
+				msgid, ok := stringLiteral(call, 0)
+				if !ok {
+					diags = append(diags, Diagnostic{
+						Position: loc,
+						Message:  fmt.Sprintf("%s: msgid argument to %s is not a string literal", loc, sel.Sel.Name),
+					})
+					return true
+				}
+				addLocation(byID, &order, msgid, "", loc)
+			case contains(cfg.PluralFns, sel.Sel.Name):
+				msgid, ok1 := stringLiteral(call, 0)
+				msgidPlural, ok2 := stringLiteral(call, 1)
+				if !ok1 || !ok2 {
+					diags = append(diags, Diagnostic{
+						Position: loc,
+						Message:  fmt.Sprintf("%s: msgid/msgid_plural arguments to %s are not string literals", loc, sel.Sel.Name),
+					})
+					return true
+				}
+				addLocation(byID, &order, msgid, msgidPlural, loc)
+			}
+			return true
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	msgs := make([]Message, 0, len(order))
+	for _, id := range order {
+		msgs = append(msgs, *byID[id])
+	}
+	sort.Slice(msgs, func(i, j int) bool { return msgs[i].MsgID < msgs[j].MsgID })
+	return msgs, diags, nil
+}
+
+func addLocation(byID map[string]*Message, order *[]string, msgid, msgidPlural, loc string) {
+	m, ok := byID[msgid]
+	if !ok {
+		m = &Message{MsgID: msgid, MsgIDPlural: msgidPlural}
+		byID[msgid] = m
+		*order = append(*order, msgid)
+	}
+	if msgidPlural != "" {
+		m.MsgIDPlural = msgidPlural
+	}
+	m.Locations = append(m.Locations, loc)
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// stringLiteral returns the value of call's argument at index if it is
+// a plain (non-interpolated) string literal.
+func stringLiteral(call *ast.CallExpr, index int) (string, bool) {
+	if index >= len(call.Args) {
+		return "", false
+	}
+	lit, ok := call.Args[index].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	s, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", false
+	}
+	return s, true
+}
+
+// packageAlias returns the local identifier file uses to refer to
+// importPath, or "" if it isn't imported.
+func packageAlias(file *ast.File, importPath string) string {
+	for _, imp := range file.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil || path != importPath {
+			continue
+		}
+		if imp.Name != nil {
+			return imp.Name.Name
+		}
+		parts := strings.Split(path, "/")
+		return parts[len(parts)-1]
+	}
+	return ""
+}
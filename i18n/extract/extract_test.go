@@ -0,0 +1,128 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2022 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package extract
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeGoFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExtractCollectsAndDeduplicates(t *testing.T) {
+	dir := t.TempDir()
+	writeGoFile(t, dir, "a.go", `package a
+
+import "github.com/flotter/golib/i18n"
+
+func f() {
+	i18n.G("hello")
+	i18n.NG("one file", "%d files", 1)
+}
+`)
+	writeGoFile(t, dir, "b.go", `package a
+
+import "github.com/flotter/golib/i18n"
+
+func g() {
+	i18n.G("hello")
+}
+`)
+
+	msgs, diags, err := Extract(dir, DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diags) != 0 {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if len(msgs) != 2 {
+		t.Fatalf("got %d messages, want 2: %v", len(msgs), msgs)
+	}
+
+	byID := make(map[string]Message, len(msgs))
+	for _, m := range msgs {
+		byID[m.MsgID] = m
+	}
+	hello, ok := byID["hello"]
+	if !ok {
+		t.Fatal(`missing "hello" message`)
+	}
+	if len(hello.Locations) != 2 {
+		t.Errorf("hello.Locations = %v, want 2 entries merged across files", hello.Locations)
+	}
+
+	plural, ok := byID["one file"]
+	if !ok {
+		t.Fatal(`missing "one file" message`)
+	}
+	if plural.MsgIDPlural != "%d files" {
+		t.Errorf("plural.MsgIDPlural = %q, want %q", plural.MsgIDPlural, "%d files")
+	}
+}
+
+func TestExtractDiagnosesNonLiteralArgument(t *testing.T) {
+	dir := t.TempDir()
+	writeGoFile(t, dir, "a.go", `package a
+
+import "github.com/flotter/golib/i18n"
+
+func f(msg string) {
+	i18n.G(msg)
+}
+`)
+
+	msgs, diags, err := Extract(dir, DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 0 {
+		t.Errorf("got %d messages, want 0: %v", len(msgs), msgs)
+	}
+	if len(diags) != 1 {
+		t.Fatalf("got %d diagnostics, want 1: %v", len(diags), diags)
+	}
+}
+
+func TestExtractIgnoresUnrelatedPackageCalls(t *testing.T) {
+	dir := t.TempDir()
+	writeGoFile(t, dir, "a.go", `package a
+
+import "fmt"
+
+func f() {
+	fmt.Println("hello")
+}
+`)
+
+	msgs, diags, err := Extract(dir, DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 0 || len(diags) != 0 {
+		t.Errorf("got msgs=%v diags=%v, want both empty", msgs, diags)
+	}
+}
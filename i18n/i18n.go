@@ -35,6 +35,12 @@
 
 package i18n
 
+import (
+	"context"
+
+	"github.com/flotter/golib/i18n/plural"
+)
+
 // API defines the interface that provides internationalisation
 // for packages. An implementation specific instance of interface
 // must be provided by the application wanting to enable
@@ -44,6 +50,28 @@ type MarkerAPI interface {
 	NG(msgid, msgidPlural string, n int) string
 }
 
+// PluralAPI is an extension interface that a MarkerAPI implementation
+// may additionally satisfy to select among more than the two English
+// plural forms. PluralForm returns the CLDR category ("zero", "one",
+// "two", "few", "many" or "other", see package i18n/plural) that n
+// falls into for lang. Implementations that do not need a language
+// parameter of their own (e.g. because they track a single default
+// language) may ignore it.
+type PluralAPI interface {
+	PluralForm(lang string, n interface{}) string
+}
+
+// ContextAPI is an optional MarkerAPI extension that a per-request
+// implementation such as Catalog may additionally satisfy to resolve
+// translations for a language carried on a context.Context (see
+// WithLang) rather than a single process-wide default. GCtx and NGCtx
+// consult it when instance implements it, falling back to G/NG
+// otherwise.
+type ContextAPI interface {
+	GCtx(ctx context.Context, msgid string) string
+	NGCtx(ctx context.Context, msgid, msgidPlural string, n int) string
+}
+
 var instance MarkerAPI
 
 // Initialise must be called before any of the API calls will
@@ -56,23 +84,70 @@ func Initialise(markers MarkerAPI) {
 // G is the shorthand for Gettext behaviour
 func G(msgid string) string {
 	if instance == nil {
+		if observer != nil {
+			observer.OnMissing("", msgid)
+		}
 		return msgid
 	} else {
 		return instance.G(msgid)
 	}
 }
 
-// NG is the shorthand for NGettext behaviour
+// NG is the shorthand for NGettext behaviour. It always delegates to
+// instance.NG first, so a rich implementation like Catalog resolves the
+// translation via its own per-category catalog entries exactly as
+// calling its NG method directly would. Only when that delegation comes
+// back untranslated (i.e. instance.NG returned msgid or msgidPlural
+// verbatim, meaning it couldn't otherwise distinguish the two) and
+// instance also implements PluralAPI is the CLDR category for n (see
+// package i18n/plural) consulted to pick the correct one of the two,
+// so implementations whose own NG only understands English's n==1 rule
+// still collapse other languages' plural rules (e.g. French treats 0
+// as "one") correctly.
 func NG(msgid string, msgidPlural string, n int) string {
 	if instance == nil {
-		if n == 1 {
+		if observer != nil {
+			observer.OnMissing("", msgid)
+		}
+		if plural.Category("en", n) == "one" {
 			// Singular
 			return msgid
 		} else {
 			// Plural
 			return msgidPlural
 		}
-	} else {
-		return instance.NG(msgid, msgidPlural, n)
 	}
+	result := instance.NG(msgid, msgidPlural, n)
+	if result != msgid && result != msgidPlural {
+		// instance.NG resolved a translation of its own; trust it
+		// rather than second-guessing it with PluralAPI.
+		return result
+	}
+	if p, ok := instance.(PluralAPI); ok {
+		if p.PluralForm("", n) == "one" {
+			return instance.G(msgid)
+		}
+		return instance.G(msgidPlural)
+	}
+	return result
+}
+
+// GCtx is the context-aware counterpart of G: when instance implements
+// ContextAPI (as Catalog does), it resolves the language to translate
+// into from ctx instead of instance's process-wide default. With no
+// instance, or one that does not implement ContextAPI, it behaves
+// exactly like G.
+func GCtx(ctx context.Context, msgid string) string {
+	if c, ok := instance.(ContextAPI); ok {
+		return c.GCtx(ctx, msgid)
+	}
+	return G(msgid)
+}
+
+// NGCtx is the context-aware counterpart of NG, mirroring GCtx.
+func NGCtx(ctx context.Context, msgid string, msgidPlural string, n int) string {
+	if c, ok := instance.(ContextAPI); ok {
+		return c.NGCtx(ctx, msgid, msgidPlural, n)
+	}
+	return NG(msgid, msgidPlural, n)
 }
@@ -0,0 +1,94 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2022 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package i18n
+
+import (
+	"testing"
+
+	"github.com/flotter/golib/i18n/plural"
+)
+
+// naiveFrenchMarker is a MarkerAPI that, like many external i18n
+// libraries, only implements the English n==1 singular/plural split in
+// its own NG, but additionally exposes the correct rule through
+// PluralAPI.
+type naiveFrenchMarker struct{}
+
+func (naiveFrenchMarker) G(msgid string) string { return msgid }
+
+func (naiveFrenchMarker) NG(msgid, msgidPlural string, n int) string {
+	if n == 1 {
+		return msgid
+	}
+	return msgidPlural
+}
+
+func (naiveFrenchMarker) PluralForm(lang string, n interface{}) string {
+	return plural.Category("fr", n)
+}
+
+func TestPackageNGConsultsPluralAPI(t *testing.T) {
+	Initialise(naiveFrenchMarker{})
+	t.Cleanup(func() { Initialise(nil) })
+
+	// French treats both 0 and 1 as the "one" category, unlike the
+	// English rule naiveFrenchMarker.NG would apply on its own.
+	if got, want := NG("fichier", "fichiers", 0), "fichier"; got != want {
+		t.Errorf("NG(..., 0) = %q, want %q", got, want)
+	}
+	if got, want := NG("fichier", "fichiers", 1), "fichier"; got != want {
+		t.Errorf("NG(..., 1) = %q, want %q", got, want)
+	}
+	if got, want := NG("fichier", "fichiers", 2), "fichiers"; got != want {
+		t.Errorf("NG(..., 2) = %q, want %q", got, want)
+	}
+}
+
+func TestPackageNGWithoutPluralAPIDelegatesToInstance(t *testing.T) {
+	Initialise(pluralOnlyMarker{})
+	t.Cleanup(func() { Initialise(nil) })
+
+	if got, want := NG("file", "files", 0), "files"; got != want {
+		t.Errorf("NG(..., 0) = %q, want %q", got, want)
+	}
+}
+
+// TestPackageNGPrefersCatalogsOwnPluralResolution guards against NG's
+// PluralAPI consultation preempting a Catalog's richer per-category
+// resolution: Catalog also implements PluralAPI, but its NG already
+// resolves n against the full CLDR category (not just "one"/"other"),
+// so the package-level NG/NGT must still return that resolution
+// untouched instead of collapsing it to instance.G(msgid/msgidPlural),
+// which would miss translations keyed only under msgid's plural group.
+func TestPackageNGPrefersCatalogsOwnPluralResolution(t *testing.T) {
+	dir := t.TempDir()
+	writeJSONCatalog(t, dir, "ru.json", `{"one file": {"one": "один файл", "few": "%d файла", "many": "%d файлов"}}`)
+
+	cat := NewCatalog(WithJSONDir(dir), WithDefaultLang("ru"))
+	Initialise(cat)
+	t.Cleanup(func() { Initialise(nil) })
+
+	if got, want := NG("one file", "%d files", 5), "%d файлов"; got != want {
+		t.Errorf("NG(..., 5) = %q, want %q", got, want)
+	}
+	if got, want := NGT("one file", "%d files", 5, nil), "%d файлов"; got != want {
+		t.Errorf("NGT(..., 5) = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,149 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2022 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package i18n
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// P is a convenience map for supplying named parameters to GT/NGT,
+// e.g. i18n.GT("hello {name}", i18n.P{"name": user}).
+type P map[string]interface{}
+
+// Interpolator is an optional MarkerAPI extension that lets an
+// implementation take over placeholder substitution in GT/NGT, e.g. to
+// reuse text/template instead of this package's default {name} syntax.
+type Interpolator interface {
+	Interpolate(translated string, data interface{}) string
+}
+
+// GT looks up msgid the same way G does, then substitutes any
+// "{name}" or "{a.b.c}" placeholders in the result with values from
+// data, which may be a map[string]interface{}, a P, or a struct.
+// Lookup and plural selection always happen before interpolation, so
+// translators are free to reorder placeholders.
+func GT(msgid string, data interface{}) string {
+	return interpolate(G(msgid), data)
+}
+
+// NGT is the GT counterpart of NG.
+func NGT(msgid, msgidPlural string, n int, data interface{}) string {
+	return interpolate(NG(msgid, msgidPlural, n), data)
+}
+
+func interpolate(translated string, data interface{}) string {
+	if i, ok := instance.(Interpolator); ok {
+		return i.Interpolate(translated, data)
+	}
+	return defaultInterpolate(translated, data)
+}
+
+// defaultInterpolate substitutes "{path}" placeholders in s with
+// values looked up from data by dotted path. "{{" is escaped to a
+// literal "{".
+func defaultInterpolate(s string, data interface{}) string {
+	var out strings.Builder
+	for {
+		start := strings.IndexByte(s, '{')
+		if start == -1 {
+			out.WriteString(s)
+			break
+		}
+		out.WriteString(s[:start])
+
+		if strings.HasPrefix(s[start:], "{{") {
+			out.WriteByte('{')
+			s = s[start+2:]
+			continue
+		}
+
+		end := strings.IndexByte(s[start:], '}')
+		if end == -1 {
+			out.WriteString(s[start:])
+			break
+		}
+		path := s[start+1 : start+end]
+		out.WriteString(formatValue(lookupPath(data, path)))
+		s = s[start+end+1:]
+	}
+	return out.String()
+}
+
+// lookupPath resolves a dotted path such as "user.name" against data,
+// which may be a map[string]interface{}, a P, or a struct (matching
+// exported field names case-insensitively; unexported fields are
+// never matched, even if their name matches the segment). It returns
+// nil if any segment cannot be resolved.
+func lookupPath(data interface{}, path string) interface{} {
+	cur := reflect.ValueOf(data)
+	for _, segment := range strings.Split(path, ".") {
+		if !cur.IsValid() {
+			return nil
+		}
+		for cur.Kind() == reflect.Ptr || cur.Kind() == reflect.Interface {
+			if cur.IsNil() {
+				return nil
+			}
+			cur = cur.Elem()
+		}
+		switch cur.Kind() {
+		case reflect.Map:
+			val := cur.MapIndex(reflect.ValueOf(segment))
+			if !val.IsValid() {
+				return nil
+			}
+			cur = val
+		case reflect.Struct:
+			field, ok := cur.Type().FieldByNameFunc(func(name string) bool {
+				return strings.EqualFold(name, segment)
+			})
+			if !ok || field.PkgPath != "" {
+				// PkgPath is non-empty for unexported fields, whose
+				// Value.Interface() would panic.
+				return nil
+			}
+			cur = cur.FieldByIndex(field.Index)
+		default:
+			return nil
+		}
+	}
+	if !cur.IsValid() {
+		return nil
+	}
+	if cur.Kind() == reflect.Interface {
+		cur = cur.Elem()
+	}
+	if !cur.IsValid() {
+		return nil
+	}
+	return cur.Interface()
+}
+
+func formatValue(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprintf("%v", v)
+}
@@ -0,0 +1,55 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2022 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package i18n
+
+import "testing"
+
+type greetData struct {
+	Name   string
+	secret string // unexported, must never be matched
+}
+
+func TestDefaultInterpolate(t *testing.T) {
+	got := defaultInterpolate("hello {name}, you have {{escaped}} and {count}", P{"name": "Ana", "count": 3})
+	want := "hello Ana, you have {escaped}} and 3"
+	if got != want {
+		t.Errorf("defaultInterpolate() = %q, want %q", got, want)
+	}
+}
+
+func TestLookupPathIgnoresUnexportedField(t *testing.T) {
+	data := greetData{Name: "Ana", secret: "internal"}
+
+	if got, want := lookupPath(data, "Name"), "Ana"; got != want {
+		t.Errorf("lookupPath(Name) = %v, want %v", got, want)
+	}
+	// "secret" only matches the unexported field; it must resolve to
+	// nil rather than panicking on Value.Interface.
+	if got := lookupPath(data, "secret"); got != nil {
+		t.Errorf("lookupPath(secret) = %v, want nil", got)
+	}
+}
+
+func TestDefaultInterpolateDoesNotPanicOnUnexportedFieldTypo(t *testing.T) {
+	got := defaultInterpolate("hi {secret}", greetData{Name: "Ana", secret: "internal"})
+	if want := "hi "; got != want {
+		t.Errorf("defaultInterpolate() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,111 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2022 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package i18n
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/flotter/golib/i18n/plural"
+)
+
+const moMagicLE = 0x950412de
+const moMagicBE = 0xde120495
+
+// loadMOFile parses a compiled gettext catalog (.mo) into a flat msgid
+// to translation map, plus a plurals map keyed by the singular msgid
+// for entries that declared more than one form. The .mo format only
+// records plural forms positionally (msgstr[0], msgstr[1], ...), so
+// lang (the catalog's BCP-47 tag) is used to map each position to its
+// CLDR category via plural.MOFormsOrder, whose categories are the ones
+// plural.Category (and so NG) returns for lang.
+func loadMOFile(path, lang string) (map[string]string, map[string]map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(data) < 28 {
+		return nil, nil, fmt.Errorf("i18n: %q is too small to be a gettext catalog", path)
+	}
+
+	var order binary.ByteOrder
+	switch binary.LittleEndian.Uint32(data[0:4]) {
+	case moMagicLE:
+		order = binary.LittleEndian
+	case moMagicBE:
+		order = binary.BigEndian
+	default:
+		return nil, nil, fmt.Errorf("i18n: %q is not a gettext MO catalog", path)
+	}
+
+	numStrings := order.Uint32(data[8:12])
+	origTableOffset := order.Uint32(data[12:16])
+	transTableOffset := order.Uint32(data[16:20])
+
+	readString := func(tableOffset, index uint32) (string, error) {
+		entry := tableOffset + index*8
+		if int(entry+8) > len(data) {
+			return "", fmt.Errorf("i18n: %q is truncated", path)
+		}
+		length := order.Uint32(data[entry : entry+4])
+		offset := order.Uint32(data[entry+4 : entry+8])
+		if int(offset+length) > len(data) {
+			return "", fmt.Errorf("i18n: %q is truncated", path)
+		}
+		return string(data[offset : offset+length]), nil
+	}
+
+	categories := plural.MOFormsOrder(lang)
+	msgs := make(map[string]string)
+	plurals := make(map[string]map[string]string)
+	for i := uint32(0); i < numStrings; i++ {
+		orig, err := readString(origTableOffset, i)
+		if err != nil {
+			return nil, nil, err
+		}
+		trans, err := readString(transTableOffset, i)
+		if err != nil {
+			return nil, nil, err
+		}
+		if orig == "" {
+			// The empty msgid holds catalog metadata (headers), not a
+			// translation.
+			continue
+		}
+		if forms := strings.Split(trans, "\x00"); len(forms) > 1 {
+			singular := strings.SplitN(orig, "\x00", 2)[0]
+			group := make(map[string]string, len(forms))
+			for n, form := range forms {
+				category := "other"
+				if n < len(categories) {
+					category = categories[n]
+				}
+				group[category] = form
+			}
+			plurals[singular] = group
+			msgs[singular] = forms[0]
+			continue
+		}
+		msgs[orig] = trans
+	}
+	return msgs, plurals, nil
+}
@@ -0,0 +1,125 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2022 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package i18n
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// buildMO assembles a minimal little-endian gettext .mo binary from
+// orig/trans pairs, mirroring the layout msgfmt produces, so tests can
+// exercise multi-form plural entries without shelling out to msgfmt.
+func buildMO(t *testing.T, name string, pairs [][2]string) string {
+	t.Helper()
+
+	type tableEntry struct{ length, offset uint32 }
+	var strs []byte
+	var origEntries, transEntries []tableEntry
+	appendString := func(s string) tableEntry {
+		e := tableEntry{length: uint32(len(s)), offset: uint32(len(strs))}
+		strs = append(strs, s...)
+		return e
+	}
+	for _, p := range pairs {
+		origEntries = append(origEntries, appendString(p[0]))
+		transEntries = append(transEntries, appendString(p[1]))
+	}
+
+	const headerLen = 28
+	tablesLen := len(pairs) * 8 * 2
+	stringsOffset := uint32(headerLen + tablesLen)
+
+	buf := make([]byte, headerLen)
+	binary.LittleEndian.PutUint32(buf[0:4], moMagicLE)
+	binary.LittleEndian.PutUint32(buf[4:8], 0) // revision
+	binary.LittleEndian.PutUint32(buf[8:12], uint32(len(pairs)))
+	binary.LittleEndian.PutUint32(buf[12:16], headerLen)
+	binary.LittleEndian.PutUint32(buf[16:20], uint32(headerLen+len(pairs)*8))
+	binary.LittleEndian.PutUint32(buf[20:24], 0) // hash table size, unused by loadMOFile
+	binary.LittleEndian.PutUint32(buf[24:28], 0) // hash table offset, unused by loadMOFile
+
+	for _, e := range origEntries {
+		var entry [8]byte
+		binary.LittleEndian.PutUint32(entry[0:4], e.length)
+		binary.LittleEndian.PutUint32(entry[4:8], e.offset+stringsOffset)
+		buf = append(buf, entry[:]...)
+	}
+	for _, e := range transEntries {
+		var entry [8]byte
+		binary.LittleEndian.PutUint32(entry[0:4], e.length)
+		binary.LittleEndian.PutUint32(entry[4:8], e.offset+stringsOffset)
+		buf = append(buf, entry[:]...)
+	}
+	buf = append(buf, strs...)
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, buf, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadMOFilePluralForms(t *testing.T) {
+	path := buildMO(t, "ru.mo", [][2]string{
+		{"one file\x00%d files", "один файл\x00%d файла\x00%d файлов"},
+	})
+
+	msgs, plurals, err := loadMOFile(path, "ru")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := msgs["one file"], "один файл"; got != want {
+		t.Errorf("msgs[%q] = %q, want %q", "one file", got, want)
+	}
+	want := map[string]string{"one": "один файл", "few": "%d файла", "many": "%d файлов"}
+	got := plurals["one file"]
+	if len(got) != len(want) {
+		t.Fatalf("plurals[%q] = %v, want %v", "one file", got, want)
+	}
+	for category, form := range want {
+		if got[category] != form {
+			t.Errorf("plurals[%q][%q] = %q, want %q", "one file", category, got[category], form)
+		}
+	}
+}
+
+func TestCatalogNGFromMOAllPluralForms(t *testing.T) {
+	path := buildMO(t, "ru.mo", [][2]string{
+		{"one file\x00%d files", "один файл\x00%d файла\x00%d файлов"},
+	})
+	cat := NewCatalog(WithGettextMO(path), WithDefaultLang("ru"))
+
+	cases := []struct {
+		n    int
+		want string
+	}{
+		{1, "один файл"},
+		{2, "%d файла"},
+		{5, "%d файлов"},
+	}
+	for _, c := range cases {
+		if got := cat.NG("one file", "%d files", c.n); got != c.want {
+			t.Errorf("NG(..., %d) = %q, want %q", c.n, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2022 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package i18n
+
+// Observer is notified of translation lookups that did not go exactly
+// as intended, so that teams can find out what actually needs
+// translating in production instead of silently seeing msgids fall
+// through. lang is "" when no language was known at the call site
+// (e.g. G/NG were called with no instance Initialised).
+type Observer interface {
+	// OnMissing is called when msgid has no translation at all in lang
+	// (or in the fallback language, if one is configured).
+	OnMissing(lang, msgid string)
+	// OnFallback is called when msgid was translated using the
+	// fallback language because it was missing from lang.
+	OnFallback(lang, msgid string)
+	// OnMalformedPlural is called when a plural translation exists for
+	// msgid but not in the CLDR category that n resolves to, and a
+	// coarser form ("other") had to be used instead.
+	OnMalformedPlural(lang, msgid string, n int)
+}
+
+var observer Observer
+
+// SetObserver registers o to be notified of translation lookups for
+// the remainder of the process, or clears the current observer when o
+// is nil. Like Initialise, it is a valid use case not to call it.
+func SetObserver(o Observer) {
+	observer = o
+}
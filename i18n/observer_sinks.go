@@ -0,0 +1,160 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2022 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Logger is the subset of *log.Logger that NewLogObserver needs,
+// satisfied by the standard library logger without an import tie-in.
+type Logger interface {
+	Printf(format string, args ...interface{})
+}
+
+// logObserver reports missing translations to a Logger.
+type logObserver struct {
+	logger Logger
+}
+
+// NewLogObserver returns an Observer that writes one line per event to
+// logger.
+func NewLogObserver(logger Logger) Observer {
+	return &logObserver{logger: logger}
+}
+
+func (o *logObserver) OnMissing(lang, msgid string) {
+	o.logger.Printf("i18n: missing translation for %q in %q", msgid, lang)
+}
+
+func (o *logObserver) OnFallback(lang, msgid string) {
+	o.logger.Printf("i18n: %q not found in %q, used fallback language", msgid, lang)
+}
+
+func (o *logObserver) OnMalformedPlural(lang, msgid string, n int) {
+	o.logger.Printf("i18n: no plural form for %q (n=%d) in %q, used \"other\"", msgid, n, lang)
+}
+
+// Counter is satisfied by a prometheus.Counter (or any single-value
+// counter with an Inc method), letting NewMetricsObserver stay free of
+// a hard dependency on the Prometheus client library.
+type Counter interface {
+	Inc()
+}
+
+// metricsObserver increments a Counter once per reported event,
+// without distinguishing lang/msgid; callers wanting labelled counters
+// should wrap per-label Counters (e.g. from a prometheus.CounterVec)
+// before passing them in.
+type metricsObserver struct {
+	missing         Counter
+	fallback        Counter
+	malformedPlural Counter
+}
+
+// NewMetricsObserver returns an Observer that increments counter every
+// time a translation is missing, and is a no-op for fallback and
+// malformed-plural events. Use NewMetricsObserverWithCounters for
+// separate counters per event kind.
+func NewMetricsObserver(counter Counter) Observer {
+	return &metricsObserver{missing: counter}
+}
+
+// NewMetricsObserverWithCounters is like NewMetricsObserver but reports
+// each event kind to its own Counter. A nil Counter disables reporting
+// for that event kind.
+func NewMetricsObserverWithCounters(missing, fallback, malformedPlural Counter) Observer {
+	return &metricsObserver{missing: missing, fallback: fallback, malformedPlural: malformedPlural}
+}
+
+func (o *metricsObserver) OnMissing(lang, msgid string) {
+	if o.missing != nil {
+		o.missing.Inc()
+	}
+}
+
+func (o *metricsObserver) OnFallback(lang, msgid string) {
+	if o.fallback != nil {
+		o.fallback.Inc()
+	}
+}
+
+func (o *metricsObserver) OnMalformedPlural(lang, msgid string, n int) {
+	if o.malformedPlural != nil {
+		o.malformedPlural.Inc()
+	}
+}
+
+// fileObserverEntry is one line appended by a fileObserver.
+type fileObserverEntry struct {
+	Event string `json:"event"`
+	Lang  string `json:"lang"`
+	MsgID string `json:"msgid"`
+}
+
+// fileObserver appends missing/fallback/malformed-plural events to a
+// JSON-lines stub file, so they can be reviewed and folded into a
+// catalog later. Writes are serialised since Observer methods may be
+// called from multiple goroutines.
+type fileObserver struct {
+	mu   sync.Mutex
+	file *os.File
+	enc  *json.Encoder
+}
+
+// NewFileObserver opens (creating if necessary) path in append mode
+// and returns an Observer that appends one JSON object per event to
+// it. The caller is responsible for eventually closing the returned
+// Observer's underlying file by calling Close.
+func NewFileObserver(path string) (*fileObserver, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("i18n: cannot open observer file %q: %w", path, err)
+	}
+	return &fileObserver{file: f, enc: json.NewEncoder(f)}, nil
+}
+
+// Close closes the underlying file.
+func (o *fileObserver) Close() error {
+	return o.file.Close()
+}
+
+func (o *fileObserver) write(entry fileObserverEntry) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	// Best effort: a failure to record a missing-translation stub
+	// should never surface as a translation error to the caller.
+	_ = o.enc.Encode(entry)
+}
+
+func (o *fileObserver) OnMissing(lang, msgid string) {
+	o.write(fileObserverEntry{Event: "missing", Lang: lang, MsgID: msgid})
+}
+
+func (o *fileObserver) OnFallback(lang, msgid string) {
+	o.write(fileObserverEntry{Event: "fallback", Lang: lang, MsgID: msgid})
+}
+
+func (o *fileObserver) OnMalformedPlural(lang, msgid string, n int) {
+	o.write(fileObserverEntry{Event: "malformed_plural", Lang: lang, MsgID: msgid})
+}
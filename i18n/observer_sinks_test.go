@@ -0,0 +1,130 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2022 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package i18n
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type fakeLogger struct {
+	lines []string
+}
+
+func (l *fakeLogger) Printf(format string, args ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+func TestLogObserverReportsAllEventKinds(t *testing.T) {
+	logger := &fakeLogger{}
+	o := NewLogObserver(logger)
+
+	o.OnMissing("fr", "hello")
+	o.OnFallback("fr", "hello")
+	o.OnMalformedPlural("ru", "one file", 5)
+
+	if got, want := len(logger.lines), 3; got != want {
+		t.Fatalf("got %d log lines, want %d: %v", got, want, logger.lines)
+	}
+}
+
+type fakeCounter struct{ count int }
+
+func (c *fakeCounter) Inc() { c.count++ }
+
+func TestMetricsObserverIncrementsOnlyMissing(t *testing.T) {
+	missing := &fakeCounter{}
+	o := NewMetricsObserver(missing)
+
+	o.OnMissing("fr", "hello")
+	o.OnFallback("fr", "hello")
+	o.OnMalformedPlural("ru", "one file", 5)
+
+	if missing.count != 1 {
+		t.Errorf("missing.count = %d, want 1", missing.count)
+	}
+}
+
+func TestMetricsObserverWithCountersPerKind(t *testing.T) {
+	missing, fallback, malformed := &fakeCounter{}, &fakeCounter{}, &fakeCounter{}
+	o := NewMetricsObserverWithCounters(missing, fallback, malformed)
+
+	o.OnMissing("fr", "hello")
+	o.OnFallback("fr", "hello")
+	o.OnMalformedPlural("ru", "one file", 5)
+
+	for name, c := range map[string]*fakeCounter{"missing": missing, "fallback": fallback, "malformed": malformed} {
+		if c.count != 1 {
+			t.Errorf("%s.count = %d, want 1", name, c.count)
+		}
+	}
+}
+
+func TestMetricsObserverWithCountersNilIsNoop(t *testing.T) {
+	o := NewMetricsObserverWithCounters(nil, nil, nil)
+	// Must not panic.
+	o.OnMissing("fr", "hello")
+	o.OnFallback("fr", "hello")
+	o.OnMalformedPlural("ru", "one file", 5)
+}
+
+func TestFileObserverAppendsJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.jsonl")
+
+	o, err := NewFileObserver(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	o.OnMissing("fr", "hello")
+	o.OnFallback("fr", "bonjour")
+	if err := o.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var entries []fileObserverEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry fileObserverEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2: %v", len(entries), entries)
+	}
+	if entries[0].Event != "missing" || entries[0].MsgID != "hello" {
+		t.Errorf("entries[0] = %+v, want event=missing msgid=hello", entries[0])
+	}
+	if entries[1].Event != "fallback" || entries[1].MsgID != "bonjour" {
+		t.Errorf("entries[1] = %+v, want event=fallback msgid=bonjour", entries[1])
+	}
+}
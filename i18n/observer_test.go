@@ -0,0 +1,70 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2022 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package i18n
+
+import "testing"
+
+type recordingObserver struct {
+	missing  []string
+	fallback []string
+}
+
+func (o *recordingObserver) OnMissing(lang, msgid string) {
+	o.missing = append(o.missing, lang+":"+msgid)
+}
+func (o *recordingObserver) OnFallback(lang, msgid string) {
+	o.fallback = append(o.fallback, lang+":"+msgid)
+}
+func (o *recordingObserver) OnMalformedPlural(lang, msgid string, n int) {}
+
+func TestSetObserverNotifiesOnUninitialisedPackage(t *testing.T) {
+	rec := &recordingObserver{}
+	SetObserver(rec)
+	t.Cleanup(func() { SetObserver(nil) })
+
+	G("hello")
+	if got, want := rec.missing, []string{":hello"}; len(got) != 1 || got[0] != want[0] {
+		t.Errorf("missing = %v, want %v", got, want)
+	}
+}
+
+func TestSetObserverNotifiesOnCatalogFallbackAndMissing(t *testing.T) {
+	dir := t.TempDir()
+	writeJSONCatalog(t, dir, "en.json", `{"hello": "hello"}`)
+	writeJSONCatalog(t, dir, "fr.json", `{}`)
+
+	rec := &recordingObserver{}
+	SetObserver(rec)
+	t.Cleanup(func() { SetObserver(nil) })
+
+	cat := NewCatalog(WithJSONDir(dir), WithDefaultLang("fr"), WithFallbackLang("en"))
+
+	if got, want := cat.G("hello"), "hello"; got != want {
+		t.Fatalf("G(hello) = %q, want %q", got, want)
+	}
+	if got, want := rec.fallback, []string{"fr:hello"}; len(got) != 1 || got[0] != want[0] {
+		t.Errorf("fallback = %v, want %v", got, want)
+	}
+
+	cat.G("nowhere")
+	if got, want := rec.missing, []string{"fr:nowhere"}; len(got) != 1 || got[0] != want[0] {
+		t.Errorf("missing = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,289 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2022 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+// Package plural implements the CLDR plural rules needed to pick the
+// correct message form for a given count, for languages whose
+// pluralisation cannot be reduced to English's singular/plural split.
+package plural
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Categories lists the CLDR plural categories, in the order they are
+// conventionally presented to translators.
+var Categories = []string{"zero", "one", "two", "few", "many", "other"}
+
+// IsCategory reports whether s is one of the CLDR plural categories.
+func IsCategory(s string) bool {
+	for _, c := range Categories {
+		if c == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Operands are the CLDR operands a plural rule is defined over, derived
+// from the count being formatted. N is the absolute value, I its
+// integer digits, V/W the number of visible/non-trailing-zero fraction
+// digits, and F/T the fraction digits themselves with and without
+// trailing zeros.
+type Operands struct {
+	N float64
+	I int64
+	V int
+	W int
+	F int64
+	T int64
+}
+
+// NewOperands derives the CLDR operands for n, which may be any integer
+// or floating point type. Non-numeric values are treated as 0.
+func NewOperands(n interface{}) Operands {
+	switch v := n.(type) {
+	case int:
+		return intOperands(int64(v))
+	case int8:
+		return intOperands(int64(v))
+	case int16:
+		return intOperands(int64(v))
+	case int32:
+		return intOperands(int64(v))
+	case int64:
+		return intOperands(v)
+	case uint:
+		return intOperands(int64(v))
+	case uint8:
+		return intOperands(int64(v))
+	case uint16:
+		return intOperands(int64(v))
+	case uint32:
+		return intOperands(int64(v))
+	case uint64:
+		return intOperands(int64(v))
+	case float32:
+		return floatOperands(float64(v))
+	case float64:
+		return floatOperands(v)
+	default:
+		return intOperands(0)
+	}
+}
+
+func intOperands(n int64) Operands {
+	abs := n
+	if abs < 0 {
+		abs = -abs
+	}
+	return Operands{N: float64(abs), I: abs}
+}
+
+func floatOperands(n float64) Operands {
+	abs := n
+	if abs < 0 {
+		abs = -abs
+	}
+	// Render with the fewest digits that round-trip to avoid binary
+	// floating point noise (e.g. 1.1 showing up as 1.10000000000000009).
+	s := strconv.FormatFloat(abs, 'f', -1, 64)
+	intPart, fracPart, hasFrac := strings.Cut(s, ".")
+	i, _ := strconv.ParseInt(intPart, 10, 64)
+	if !hasFrac || fracPart == "" {
+		return Operands{N: abs, I: i}
+	}
+	f, _ := strconv.ParseInt(fracPart, 10, 64)
+	t := strings.TrimRight(fracPart, "0")
+	var tVal int64
+	if t != "" {
+		tVal, _ = strconv.ParseInt(t, 10, 64)
+	}
+	return Operands{
+		N: abs,
+		I: i,
+		V: len(fracPart),
+		W: len(t),
+		F: f,
+		T: tVal,
+	}
+}
+
+// RuleFunc maps a count's CLDR operands to a plural category.
+type RuleFunc func(Operands) string
+
+func inRange(v int64, lo, hi int64) bool {
+	return v >= lo && v <= hi
+}
+
+// rules holds one plural rule per supported base language tag. It
+// deliberately covers the common set rather than the full CLDR
+// dataset; languages not listed here fall back to "en".
+var rules = map[string]RuleFunc{
+	"en": func(o Operands) string {
+		if o.I == 1 && o.V == 0 {
+			return "one"
+		}
+		return "other"
+	},
+	"fr": func(o Operands) string {
+		if o.I == 0 || o.I == 1 {
+			return "one"
+		}
+		return "other"
+	},
+	"es": func(o Operands) string {
+		if o.N == 1 {
+			return "one"
+		}
+		return "other"
+	},
+	"de": func(o Operands) string {
+		if o.I == 1 && o.V == 0 {
+			return "one"
+		}
+		return "other"
+	},
+	"ja": func(o Operands) string {
+		return "other"
+	},
+	"zh": func(o Operands) string {
+		return "other"
+	},
+	"ru": func(o Operands) string {
+		if o.V == 0 && o.I%10 == 1 && o.I%100 != 11 {
+			return "one"
+		}
+		if o.V == 0 && inRange(o.I%10, 2, 4) && !inRange(o.I%100, 12, 14) {
+			return "few"
+		}
+		if o.V == 0 && (o.I%10 == 0 || inRange(o.I%10, 5, 9) || inRange(o.I%100, 11, 14)) {
+			return "many"
+		}
+		return "other"
+	},
+	"pl": func(o Operands) string {
+		if o.I == 1 && o.V == 0 {
+			return "one"
+		}
+		if o.V == 0 && inRange(o.I%10, 2, 4) && !inRange(o.I%100, 12, 14) {
+			return "few"
+		}
+		if o.V == 0 && ((o.I != 1 && inRange(o.I%10, 0, 1)) || inRange(o.I%10, 5, 9) || inRange(o.I%100, 12, 14)) {
+			return "many"
+		}
+		return "other"
+	},
+	"ar": func(o Operands) string {
+		if o.N == 0 {
+			return "zero"
+		}
+		if o.N == 1 {
+			return "one"
+		}
+		if o.N == 2 {
+			return "two"
+		}
+		if o.V == 0 && inRange(o.I%100, 3, 10) {
+			return "few"
+		}
+		if o.V == 0 && inRange(o.I%100, 11, 99) {
+			return "many"
+		}
+		return "other"
+	},
+	"cy": func(o Operands) string {
+		switch o.N {
+		case 0:
+			return "zero"
+		case 1:
+			return "one"
+		case 2:
+			return "two"
+		case 3:
+			return "few"
+		case 6:
+			return "many"
+		default:
+			return "other"
+		}
+	},
+}
+
+// moFormsOrder lists, for each supported base language tag, the CLDR
+// categories in the order gettext assigns them to a compiled .mo
+// catalog's plural index (msgstr[0], msgstr[1], ...), mirroring each
+// language's canonical Plural-Forms header. Languages not listed here
+// fall back to "en", matching Category.
+var moFormsOrder = map[string][]string{
+	"en": {"one", "other"},
+	"fr": {"one", "other"},
+	"es": {"one", "other"},
+	"de": {"one", "other"},
+	"ja": {"other"},
+	"zh": {"other"},
+	"ru": {"one", "few", "many"},
+	"pl": {"one", "few", "many"},
+	"ar": {"zero", "one", "two", "few", "many", "other"},
+	"cy": {"zero", "one", "two", "few", "many", "other"},
+}
+
+// MOFormsOrder returns the CLDR categories in gettext's .mo plural
+// index order for lang, falling back from a region-specific tag
+// (e.g. "pt-BR") to its base language (e.g. "pt") and finally to
+// "en", the same way Category does.
+func MOFormsOrder(lang string) []string {
+	if order, ok := moFormsOrder[lang]; ok {
+		return order
+	}
+	if base, _, ok := strings.Cut(lang, "-"); ok {
+		if order, ok := moFormsOrder[base]; ok {
+			return order
+		}
+	}
+	return moFormsOrder["en"]
+}
+
+// Category returns the CLDR plural category ("zero", "one", "two",
+// "few", "many" or "other") that applies to n in lang, falling back
+// from a region-specific tag (e.g. "pt-BR") to its base language
+// (e.g. "pt") and finally to "en" when lang has no dedicated rule.
+func Category(lang string, n interface{}) string {
+	ops := NewOperands(n)
+	if fn, ok := rules[lang]; ok {
+		return fn(ops)
+	}
+	if base, _, ok := strings.Cut(lang, "-"); ok {
+		if fn, ok := rules[base]; ok {
+			return fn(ops)
+		}
+	}
+	return rules["en"](ops)
+}
+
+// RegisterRule adds or overrides the plural rule used for lang. It
+// exists so embedders can supply a rule for a language missing from
+// the built-in set without forking this package.
+func RegisterRule(lang string, rule RuleFunc) {
+	if rule == nil {
+		panic(fmt.Sprintf("plural: nil rule for %q", lang))
+	}
+	rules[lang] = rule
+}
@@ -0,0 +1,123 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2022 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package plural
+
+import "testing"
+
+func TestCategoryEnglish(t *testing.T) {
+	cases := map[int]string{0: "other", 1: "one", 2: "other", 100: "other"}
+	for n, want := range cases {
+		if got := Category("en", n); got != want {
+			t.Errorf("Category(en, %d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestCategoryFrenchTreatsZeroAsOne(t *testing.T) {
+	cases := map[int]string{0: "one", 1: "one", 2: "other"}
+	for n, want := range cases {
+		if got := Category("fr", n); got != want {
+			t.Errorf("Category(fr, %d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestCategoryRussianThreeForms(t *testing.T) {
+	cases := map[int]string{1: "one", 21: "one", 2: "few", 3: "few", 24: "few", 5: "many", 11: "many", 12: "many"}
+	for n, want := range cases {
+		if got := Category("ru", n); got != want {
+			t.Errorf("Category(ru, %d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestCategoryArabicSixForms(t *testing.T) {
+	cases := map[int]string{0: "zero", 1: "one", 2: "two", 5: "few", 15: "many", 100: "other"}
+	for n, want := range cases {
+		if got := Category("ar", n); got != want {
+			t.Errorf("Category(ar, %d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestCategoryFallsBackFromRegionToBaseLanguage(t *testing.T) {
+	if got, want := Category("fr-CA", 0), Category("fr", 0); got != want {
+		t.Errorf("Category(fr-CA, 0) = %q, want %q (base language)", got, want)
+	}
+}
+
+func TestCategoryFallsBackToEnglishForUnknownLanguage(t *testing.T) {
+	if got, want := Category("xx", 1), "one"; got != want {
+		t.Errorf("Category(xx, 1) = %q, want %q", got, want)
+	}
+	if got, want := Category("xx", 2), "other"; got != want {
+		t.Errorf("Category(xx, 2) = %q, want %q", got, want)
+	}
+}
+
+func TestRegisterRuleAddsLanguage(t *testing.T) {
+	RegisterRule("xy", func(o Operands) string {
+		if o.I == 7 {
+			return "few"
+		}
+		return "other"
+	})
+	if got, want := Category("xy", 7), "few"; got != want {
+		t.Errorf("Category(xy, 7) = %q, want %q", got, want)
+	}
+}
+
+func TestRegisterRulePanicsOnNil(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("RegisterRule(nil) did not panic")
+		}
+	}()
+	RegisterRule("xz", nil)
+}
+
+func TestIsCategory(t *testing.T) {
+	if !IsCategory("few") {
+		t.Error("IsCategory(few) = false, want true")
+	}
+	if IsCategory("plural") {
+		t.Error("IsCategory(plural) = true, want false")
+	}
+}
+
+func TestMOFormsOrderMatchesRussianRule(t *testing.T) {
+	order := MOFormsOrder("ru")
+	want := []string{"one", "few", "many"}
+	if len(order) != len(want) {
+		t.Fatalf("MOFormsOrder(ru) = %v, want %v", order, want)
+	}
+	for i, c := range want {
+		if order[i] != c {
+			t.Errorf("MOFormsOrder(ru)[%d] = %q, want %q", i, order[i], c)
+		}
+	}
+}
+
+func TestMOFormsOrderFallsBackToEnglish(t *testing.T) {
+	order := MOFormsOrder("xx")
+	if len(order) != 2 || order[0] != "one" || order[1] != "other" {
+		t.Errorf("MOFormsOrder(xx) = %v, want [one other]", order)
+	}
+}